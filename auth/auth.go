@@ -0,0 +1,411 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// contextKey — приватный тип для ключей контекста, чтобы избежать коллизий с другими пакетами.
+type contextKey string
+
+const userContextKey contextKey = "auth.user"
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// Типы токенов, записанные в Claims.TokenType — не дают предъявить refresh-токен
+// там, где ожидается access, и наоборот.
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+)
+
+// User описывает аутентифицированного пользователя, извлечённого из JWT.
+type User struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+// Claims — набор полей, которые мы кладём в JWT.
+type Claims struct {
+	Role      string `json:"role"`
+	TokenType string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type tokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+var secret []byte
+
+// SetSecret configures the HMAC key used to sign and verify JWTs. It must be called
+// once at startup (see main.go, which loads it from the required JWT_SECRET env var)
+// before any token is issued or parsed — there is no built-in default, so a deployment
+// that forgets to configure it fails to start rather than signing with a known key.
+func SetSecret(s []byte) {
+	secret = s
+}
+
+func jwtSecret() []byte {
+	return secret
+}
+
+// hashPassword хэширует пароль с помощью bcrypt перед сохранением в users.password_hash.
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkPassword сверяет пароль с хэшем из базы.
+func checkPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// generateToken подписывает HS256 JWT с sub/role/typ/exp/iat/jti. tokenType
+// ("access"/"refresh") обязателен — именно по нему caller'ы отличают, где токен
+// можно предъявлять, независимо от совпадающих sub/role.
+func generateToken(userID, role, tokenType string, ttl time.Duration) (string, string, error) {
+	jti := uuid.New().String()
+	now := time.Now()
+
+	claims := Claims{
+		Role:      role,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(jwtSecret())
+	if err != nil {
+		return "", "", err
+	}
+	return signed, jti, nil
+}
+
+// parseToken валидирует подпись и возвращает Claims, если токен ещё действителен.
+// jwt.WithValidMethods закрывает alg confusion: без него keyfunc отдал бы HMAC-секрет
+// и для токена, подписанного сменой на alg=none или RS256 с публичным ключом как секретом.
+func parseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return jwtSecret(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// revokeToken кладёт jti в чёрный список, делая соответствующий токен недействительным.
+func revokeToken(ctx context.Context, db *pgxpool.Pool, jti string, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING`
+	_, err := db.Exec(ctx, query, jti, expiresAt)
+	return err
+}
+
+// isRevoked проверяет, попал ли jti в чёрный список отозванных токенов.
+func isRevoked(ctx context.Context, db *pgxpool.Pool, jti string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`
+	err := db.QueryRow(ctx, query, jti).Scan(&exists)
+	return exists, err
+}
+
+// Login проверяет логин/пароль и выдаёт пару access/refresh токенов.
+func Login(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		var creds credentials
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+			logger.Errorf("Error decoding login request: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		var userID, passwordHash, role string
+		query := `SELECT id, password_hash, role FROM users WHERE username = $1`
+		err := db.QueryRow(ctx, query, creds.Username).Scan(&userID, &passwordHash, &role)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				logger.Warnf("Login denied: unknown username %q", creds.Username)
+				http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+				return
+			}
+			logger.Errorf("Error querying user: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if !checkPassword(passwordHash, creds.Password) {
+			logger.Warnf("Login denied: bad password for username %q", creds.Username)
+			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+			return
+		}
+
+		access, _, err := generateToken(userID, role, tokenTypeAccess, accessTokenTTL)
+		if err != nil {
+			logger.Errorf("Error generating access token: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		refresh, _, err := generateToken(userID, role, tokenTypeRefresh, refreshTokenTTL)
+		if err != nil {
+			logger.Errorf("Error generating refresh token: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Infof("User %s logged in", userID)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(tokenPair{AccessToken: access, RefreshToken: refresh})
+	}
+}
+
+type newUser struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// CreateUser заводит нового пользователя с bcrypt-хэшем пароля. Доступен только admin —
+// это единственный путь провижининга учёток, так как self-signup не предусмотрен.
+func CreateUser(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		var u newUser
+		if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+			logger.Errorf("Error decoding create user request: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if u.Username == "" || u.Password == "" || u.Role == "" {
+			http.Error(w, "username, password and role are required", http.StatusBadRequest)
+			return
+		}
+
+		hash, err := hashPassword(u.Password)
+		if err != nil {
+			logger.Errorf("Error hashing password: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id := uuid.New().String()
+		query := `INSERT INTO users (id, username, password_hash, role) VALUES ($1, $2, $3, $4)`
+		if _, err := db.Exec(ctx, query, id, u.Username, hash, u.Role); err != nil {
+			logger.Errorf("Error creating user: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Infof("User %s (%s) created with role %s", id, u.Username, u.Role)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(User{ID: id, Role: u.Role})
+	}
+}
+
+// Refresh выдаёт новую пару токенов по действующему refresh-токену.
+func Refresh(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		var body struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			logger.Errorf("Error decoding refresh request: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		claims, err := parseToken(body.RefreshToken)
+		if err != nil {
+			logger.Warnf("Refresh denied: invalid token: %v", err)
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+		if claims.TokenType != tokenTypeRefresh {
+			logger.Warnf("Refresh denied: token %s is not a refresh token", claims.ID)
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		revoked, err := isRevoked(ctx, db, claims.ID)
+		if err != nil {
+			logger.Errorf("Error checking revocation list: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if revoked {
+			logger.Warnf("Refresh denied: token %s is revoked", claims.ID)
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		access, _, err := generateToken(claims.Subject, claims.Role, tokenTypeAccess, accessTokenTTL)
+		if err != nil {
+			logger.Errorf("Error generating access token: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		refresh, _, err := generateToken(claims.Subject, claims.Role, tokenTypeRefresh, refreshTokenTTL)
+		if err != nil {
+			logger.Errorf("Error generating refresh token: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		// Rotate: the presented refresh token must not be replayable once it has
+		// been exchanged for a new pair.
+		if err := revokeToken(ctx, db, claims.ID, claims.ExpiresAt.Time); err != nil {
+			logger.Errorf("Error revoking used refresh token: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(tokenPair{AccessToken: access, RefreshToken: refresh})
+	}
+}
+
+// Logout кладёт jti текущего access-токена и, если он передан в теле запроса,
+// парного refresh-токена в чёрный список, делая оба недействительными.
+func Logout(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		claims, ok := claimsFromRequest(r)
+		if !ok {
+			http.Error(w, "Missing or invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := revokeToken(ctx, db, claims.ID, claims.ExpiresAt.Time); err != nil {
+			logger.Errorf("Error revoking token: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if body.RefreshToken != "" {
+			refreshClaims, err := parseToken(body.RefreshToken)
+			if err != nil || refreshClaims.TokenType != tokenTypeRefresh {
+				logger.Warnf("Logout: ignoring invalid refresh token: %v", err)
+			} else if err := revokeToken(ctx, db, refreshClaims.ID, refreshClaims.ExpiresAt.Time); err != nil {
+				logger.Errorf("Error revoking refresh token: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		logger.Infof("User %s logged out, token %s revoked", claims.Subject, claims.ID)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Logged out"))
+	}
+}
+
+// claimsFromRequest извлекает и проверяет подпись bearer-токена из заголовка
+// Authorization. Требует access-токен: без этой проверки 7-дневный refresh-токен
+// работал бы как bearer на защищённых маршрутах, игнорируя 15-минутный access TTL.
+func claimsFromRequest(r *http.Request) (*Claims, bool) {
+	header := r.Header.Get("Authorization")
+	if len(header) < 8 || header[:7] != "Bearer " {
+		return nil, false
+	}
+
+	claims, err := parseToken(header[7:])
+	if err != nil || claims.TokenType != tokenTypeAccess {
+		return nil, false
+	}
+	return claims, true
+}
+
+// RequireRole возвращает middleware, которое требует валидный, неотозванный JWT
+// с одной из перечисленных ролей, и кладёт auth.User в контекст запроса.
+func RequireRole(db *pgxpool.Pool, logger *zap.SugaredLogger, roles ...string) func(httprouter.Handle) httprouter.Handle {
+	allowed := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		allowed[role] = struct{}{}
+	}
+
+	return func(next httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			claims, ok := claimsFromRequest(r)
+			if !ok {
+				logger.Warnf("Access denied: missing or invalid token for %s %s", r.Method, r.URL.Path)
+				http.Error(w, "Missing or invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+			defer cancel()
+
+			revoked, err := isRevoked(ctx, db, claims.ID)
+			if err != nil {
+				logger.Errorf("Error checking revocation list: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if revoked {
+				logger.Warnf("Access denied: token %s is revoked", claims.ID)
+				http.Error(w, "Missing or invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			if _, ok := allowed[claims.Role]; !ok {
+				logger.Warnf("Access denied: role %q not permitted for %s %s", claims.Role, r.Method, r.URL.Path)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			user := User{ID: claims.Subject, Role: claims.Role}
+			ctx = context.WithValue(r.Context(), userContextKey, user)
+			next(w, r.WithContext(ctx), ps)
+		}
+	}
+}
+
+// UserFromContext достаёт аутентифицированного пользователя, положенного RequireRole.
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userContextKey).(User)
+	return user, ok
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/caarlos0/env/v9"
+)
+
+// Config описывает все настройки сервиса, читаемые из переменных окружения.
+type Config struct {
+	HTTPAddr string `env:"HTTP_ADDR" envDefault:":8080"`
+	GRPCAddr string `env:"GRPC_ADDR" envDefault:":9090"`
+
+	PGHost     string `env:"PG_HOST" envDefault:"localhost"`
+	PGPort     int    `env:"PG_PORT" envDefault:"5432"`
+	PGUser     string `env:"PG_USER" envDefault:"postgres"`
+	PGPassword string `env:"PG_PWD"`
+	PGDatabase string `env:"PG_DB" envDefault:"client_service"`
+	PGMaxConns int32  `env:"PG_MAX_CONNS" envDefault:"10"`
+
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"15s"`
+
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`
+	LogFormat string `env:"LOG_FORMAT" envDefault:"json"`
+
+	OutboxPollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"2s"`
+	OutboxMaxAttempts  int           `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"5"`
+	OutboxWebhookURL   string        `env:"OUTBOX_WEBHOOK_URL"`
+
+	// JWTSecret подписывает и проверяет выданные JWT. Обязателен: без required
+	// деплой без переменной окружения подписывал бы токены публично известным
+	// ключом по умолчанию вместо отказа при старте.
+	JWTSecret string `env:"JWT_SECRET,required"`
+}
+
+// Load читает Config из переменных окружения.
+func Load() (Config, error) {
+	cfg := Config{}
+	if err := env.Parse(&cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing config: %w", err)
+	}
+	return cfg, nil
+}
+
+// DSN собирает строку подключения Postgres из отдельных PG_* полей.
+func (c Config) DSN() string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s",
+		c.PGUser, c.PGPassword, c.PGHost, c.PGPort, c.PGDatabase,
+	)
+}
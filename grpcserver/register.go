@@ -0,0 +1,40 @@
+package grpcserver
+
+import (
+	"net"
+
+	"client_service/proto"
+	"client_service/repository"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// New собирает *grpc.Server с зарегистрированным ClientService, health-check
+// (grpc.health.v1) и включённой reflection, чтобы им можно было пользоваться
+// из grpcurl и подобных инструментов без дополнительной настройки.
+func New(repo repository.ClientRepo, logger *zap.SugaredLogger) *grpc.Server {
+	srv := grpc.NewServer()
+
+	proto.RegisterClientServiceServer(srv, NewServer(repo, logger))
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	reflection.Register(srv)
+
+	return srv
+}
+
+// Serve запускает srv на заданном адресе; вызов блокируется до остановки сервера.
+func Serve(srv *grpc.Server, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(lis)
+}
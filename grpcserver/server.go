@@ -0,0 +1,139 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"client_service/proto"
+	"client_service/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server реализует proto.ClientServiceServer поверх общего repository.ClientRepo,
+// используемого и HTTP, и gRPC транспортами.
+type Server struct {
+	proto.UnimplementedClientServiceServer
+
+	repo   repository.ClientRepo
+	logger *zap.SugaredLogger
+}
+
+// NewServer создаёт gRPC-реализацию ClientService.
+func NewServer(repo repository.ClientRepo, logger *zap.SugaredLogger) *Server {
+	return &Server{repo: repo, logger: logger}
+}
+
+func toProto(c repository.Client) *proto.Client {
+	return &proto.Client{Id: c.ID, Name: c.Name, Phone: c.Phone, Email: c.Email, Comment: c.Comment}
+}
+
+// translateErr преобразует ошибки репозитория в коды состояния gRPC.
+func translateErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return status.Error(codes.NotFound, "client not found")
+	}
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func (s *Server) CreateClient(ctx context.Context, req *proto.CreateClientRequest) (*proto.CreateClientResponse, error) {
+	if req.GetName() == "" || req.GetEmail() == "" || req.GetPhone() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name, phone and email are required")
+	}
+
+	client := repository.Client{
+		ID:      uuid.New().String(),
+		Name:    req.GetName(),
+		Phone:   req.GetPhone(),
+		Email:   req.GetEmail(),
+		Comment: req.GetComment(),
+	}
+
+	created, err := s.repo.Create(ctx, client)
+	if err != nil {
+		s.logger.Errorf("grpc CreateClient: %v", err)
+		return nil, translateErr(err)
+	}
+	return &proto.CreateClientResponse{Client: toProto(created)}, nil
+}
+
+func (s *Server) GetClient(ctx context.Context, req *proto.GetClientRequest) (*proto.GetClientResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	client, err := s.repo.Get(ctx, req.GetId())
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Errorf("grpc GetClient: %v", err)
+		}
+		return nil, translateErr(err)
+	}
+	return &proto.GetClientResponse{Client: toProto(client)}, nil
+}
+
+func (s *Server) ListClients(ctx context.Context, req *proto.ListClientsRequest) (*proto.ListClientsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := int(req.GetOffset())
+	if offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must be non-negative")
+	}
+
+	clients, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		s.logger.Errorf("grpc ListClients: %v", err)
+		return nil, translateErr(err)
+	}
+
+	resp := &proto.ListClientsResponse{Clients: make([]*proto.Client, 0, len(clients))}
+	for _, c := range clients {
+		resp.Clients = append(resp.Clients, toProto(c))
+	}
+	return resp, nil
+}
+
+func (s *Server) UpdateClient(ctx context.Context, req *proto.UpdateClientRequest) (*proto.UpdateClientResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	client := repository.Client{
+		ID:      req.GetId(),
+		Name:    req.GetName(),
+		Phone:   req.GetPhone(),
+		Email:   req.GetEmail(),
+		Comment: req.GetComment(),
+	}
+
+	updated, err := s.repo.Update(ctx, client)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Errorf("grpc UpdateClient: %v", err)
+		}
+		return nil, translateErr(err)
+	}
+	return &proto.UpdateClientResponse{Client: toProto(updated)}, nil
+}
+
+func (s *Server) DeleteClient(ctx context.Context, req *proto.DeleteClientRequest) (*proto.DeleteClientResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := s.repo.Delete(ctx, req.GetId()); err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Errorf("grpc DeleteClient: %v", err)
+		}
+		return nil, translateErr(err)
+	}
+	return &proto.DeleteClientResponse{}, nil
+}
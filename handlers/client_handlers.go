@@ -1,28 +1,41 @@
 package handlers
 
 import (
-	"context"
+	"client_service/middleware"
+	"client_service/repository"
 	"encoding/json"
-	"github.com/jackc/pgx/v5"
-	"go.uber.org/zap"
+	"errors"
 	"net/http"
 	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/julienschmidt/httprouter"
 )
 
 type Client struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Phone   string `json:"phone"`
-	Email   string `json:"email"`
-	Comment string `json:"comment,omitempty"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	Email     string    `json:"email"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
 }
 
+// clientsPage — ответ GetClients: страница клиентов плюс курсор на следующую.
+type clientsPage struct {
+	Items      []Client `json:"items"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// allowedSortFields и allowedOrders — белый список значений ?sort= и ?order=,
+// чтобы никогда не подставлять непроверенный ввод пользователя в ORDER BY.
+var (
+	allowedSortFields = map[string]bool{"name": true, "created_at": true}
+	allowedOrders     = map[string]bool{"asc": true, "desc": true}
+)
+
 func validatePhone(phone string) bool {
 	re := regexp.MustCompile(`^\+?\d{10,15}$`)
 	return re.MatchString(phone)
@@ -33,9 +46,19 @@ func validateEmail(email string) bool {
 	return re.MatchString(email)
 }
 
+func toDomain(c Client) repository.Client {
+	return repository.Client{ID: c.ID, Name: c.Name, Phone: c.Phone, Email: c.Email, Comment: c.Comment}
+}
+
+func fromDomain(c repository.Client) Client {
+	return Client{ID: c.ID, Name: c.Name, Phone: c.Phone, Email: c.Email, Comment: c.Comment, CreatedAt: c.CreatedAt}
+}
+
 // CreateClient добавляет нового клиента
-func CreateClient(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+func CreateClient(repo repository.ClientRepo) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		logger := middleware.LoggerFromContext(r.Context())
+
 		var client Client
 		if err := json.NewDecoder(r.Body).Decode(&client); err != nil {
 			logger.Errorf("Error decoding JSON: %v", err)
@@ -61,11 +84,7 @@ func CreateClient(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle
 		client.ID = uuid.New().String()
 		logger.Infof("Generated UUID: %s", client.ID)
 
-		query := `INSERT INTO clients (id, name, phone, email, comment) VALUES ($1, $2, $3, $4, $5)`
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		_, err := db.Exec(ctx, query, client.ID, client.Name, client.Phone, client.Email, client.Comment)
+		created, err := repo.Create(r.Context(), toDomain(client))
 		if err != nil {
 			logger.Errorf("Error inserting client into database: %v", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -74,23 +93,19 @@ func CreateClient(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle
 
 		logger.Info("Client successfully created")
 		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(client)
+		json.NewEncoder(w).Encode(fromDomain(created))
 	}
 }
 
 // GetClientByID получает клиента по ID
-func GetClientByID(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+func GetClientByID(repo repository.ClientRepo) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		logger := middleware.LoggerFromContext(r.Context())
 		id := ps.ByName("id")
-		var client Client
-
-		query := `SELECT id, name, phone, email, comment FROM clients WHERE id = $1`
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
 
-		err := db.QueryRow(ctx, query, id).Scan(&client.ID, &client.Name, &client.Phone, &client.Email, &client.Comment)
+		client, err := repo.Get(r.Context(), id)
 		if err != nil {
-			if err == pgx.ErrNoRows {
+			if errors.Is(err, repository.ErrNotFound) {
 				http.Error(w, "Client not found", http.StatusNotFound)
 				return
 			}
@@ -100,70 +115,113 @@ func GetClientByID(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handl
 		}
 
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(client)
+		json.NewEncoder(w).Encode(fromDomain(client))
 	}
 }
 
-// GetClients получает список клиентов с пагинацией
-func GetClients(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+// parseListCursorParams читает и валидирует ?cursor=&limit=&sort=&order=&q=&email=
+// общие для GetClients и GetClientsCount.
+func parseListCursorParams(r *http.Request) (repository.ListCursorParams, bool) {
+	logger := middleware.LoggerFromContext(r.Context())
+	q := r.URL.Query()
+	params := repository.ListCursorParams{
+		Cursor: q.Get("cursor"),
+		Sort:   q.Get("sort"),
+		Order:  q.Get("order"),
+		Query:  q.Get("q"),
+		Email:  q.Get("email"),
+		Limit:  10,
+	}
+
+	if params.Sort == "" {
+		params.Sort = "created_at"
+	}
+	if params.Order == "" {
+		params.Order = "asc"
+	}
+	if !allowedSortFields[params.Sort] {
+		logger.Warnf("Invalid sort value: %s", params.Sort)
+		return repository.ListCursorParams{}, false
+	}
+	if !allowedOrders[params.Order] {
+		logger.Warnf("Invalid order value: %s", params.Order)
+		return repository.ListCursorParams{}, false
+	}
+
+	if l := q.Get("limit"); l != "" {
+		val, err := strconv.Atoi(l)
+		if err != nil || val <= 0 {
+			logger.Warnf("Invalid limit value: %s", l)
+			return repository.ListCursorParams{}, false
+		}
+		params.Limit = val
+	}
+
+	return params, true
+}
+
+// GetClients получает список клиентов с keyset-пагинацией, фильтрацией и сортировкой
+func GetClients(repo repository.ClientRepo) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-		limit := 10
-		offset := 0
-
-		if l := r.URL.Query().Get("limit"); l != "" {
-			val, err := strconv.Atoi(l)
-			if err != nil || val < 0 {
-				logger.Warnf("Invalid limit value: %s", l)
-				http.Error(w, "Invalid limit value", http.StatusBadRequest)
-				return
-			}
-			limit = val
+		logger := middleware.LoggerFromContext(r.Context())
+
+		params, ok := parseListCursorParams(r)
+		if !ok {
+			http.Error(w, "Invalid query parameters", http.StatusBadRequest)
+			return
 		}
 
-		if o := r.URL.Query().Get("offset"); o != "" {
-			val, err := strconv.Atoi(o)
-			if err != nil || val < 0 {
-				logger.Warnf("Invalid offset value: %s", o)
-				http.Error(w, "Invalid offset value", http.StatusBadRequest)
+		logger.Infof("Fetching clients with params: %+v", params)
+
+		page, err := repo.ListCursor(r.Context(), params)
+		if err != nil {
+			if errors.Is(err, repository.ErrInvalidCursor) {
+				http.Error(w, "Invalid cursor", http.StatusBadRequest)
 				return
 			}
-			offset = val
+			logger.Errorf("Error fetching clients: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
 		}
 
-		logger.Infof("Fetching clients with limit: %d and offset: %d", limit, offset)
+		result := clientsPage{Items: make([]Client, 0, len(page.Items)), NextCursor: page.NextCursor}
+		for _, c := range page.Items {
+			result.Items = append(result.Items, fromDomain(c))
+		}
 
-		query := `SELECT id, name, phone, email, comment FROM clients LIMIT $1 OFFSET $2`
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+		logger.Infof("Fetched %d clients", len(result.Items))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
 
-		rows, err := db.Query(ctx, query, limit, offset)
-		if err != nil {
-			logger.Errorf("Error fetching clients: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+// GetClientsCount возвращает общее число клиентов, удовлетворяющих тем же фильтрам, что и GetClients
+func GetClientsCount(repo repository.ClientRepo) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		logger := middleware.LoggerFromContext(r.Context())
+
+		params, ok := parseListCursorParams(r)
+		if !ok {
+			http.Error(w, "Invalid query parameters", http.StatusBadRequest)
 			return
 		}
-		defer rows.Close()
 
-		clients := []Client{}
-		for rows.Next() {
-			var client Client
-			if err := rows.Scan(&client.ID, &client.Name, &client.Phone, &client.Email, &client.Comment); err != nil {
-				logger.Errorf("Error scanning client row: %v", err)
-				http.Error(w, "Internal server error", http.StatusInternalServerError)
-				return
-			}
-			clients = append(clients, client)
+		total, err := repo.Count(r.Context(), params)
+		if err != nil {
+			logger.Errorf("Error counting clients: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
 		}
 
-		logger.Infof("Fetched %d clients", len(clients))
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(clients)
+		json.NewEncoder(w).Encode(map[string]int64{"count": total})
 	}
 }
 
 // UpdateClient обновляет данные клиента
-func UpdateClient(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+func UpdateClient(repo repository.ClientRepo) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		logger := middleware.LoggerFromContext(r.Context())
 		id := ps.ByName("id")
 		logger.Infof("Updating client with ID: %s", id)
 
@@ -190,52 +248,44 @@ func UpdateClient(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle
 			return
 		}
 
-		query := `UPDATE clients SET name = $1, phone = $2, email = $3, comment = $4 WHERE id = $5`
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		cmdTag, err := db.Exec(ctx, query, client.Name, client.Phone, client.Email, client.Comment, id)
+		client.ID = id
+		updated, err := repo.Update(r.Context(), toDomain(client))
 		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				logger.Warnf("Client with ID %s not found", id)
+				http.Error(w, "Client not found", http.StatusNotFound)
+				return
+			}
 			logger.Errorf("Error updating client: %v", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
-		if cmdTag.RowsAffected() == 0 {
-			logger.Warnf("Client with ID %s not found", id)
-			http.Error(w, "Client not found", http.StatusNotFound)
-			return
-		}
-
-		logger.Infof("Successfully updated client: %+v", client)
+		logger.Infof("Successfully updated client: %+v", updated)
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(client)
+		json.NewEncoder(w).Encode(fromDomain(updated))
 	}
 }
 
 // DeleteClient удаляет клиента
-func DeleteClient(db *pgxpool.Pool, logger *zap.SugaredLogger) httprouter.Handle {
+func DeleteClient(repo repository.ClientRepo) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		logger := middleware.LoggerFromContext(r.Context())
 		id := ps.ByName("id")
 		logger.Infof("Deleting client with ID: %s", id)
 
-		query := `DELETE FROM clients WHERE id = $1`
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		cmdTag, err := db.Exec(ctx, query, id)
+		err := repo.Delete(r.Context(), id)
 		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				logger.Warnf("Client with ID %s not found", id)
+				http.Error(w, "Client not found", http.StatusNotFound)
+				return
+			}
 			logger.Errorf("Error deleting client: %v", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
-		if cmdTag.RowsAffected() == 0 {
-			logger.Warnf("Client with ID %s not found", id)
-			http.Error(w, "Client not found", http.StatusNotFound)
-			return
-		}
-
 		logger.Infof("Successfully deleted client with ID: %s", id)
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Client deleted"))
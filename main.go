@@ -1,49 +1,127 @@
 package main
 
 import (
+	"client_service/auth"
+	"client_service/config"
+	"client_service/grpcserver"
+	"client_service/outbox"
+	"client_service/repository"
 	"client_service/router"
 	"context"
-	"github.com/jackc/pgx/v5/pgxpool"
-	"go.uber.org/zap"
+	"errors"
 	"log"
 	"net/http"
-	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sync/errgroup"
 )
 
-// Глобальная переменная для доступа к базе данных
-var DB *pgxpool.Pool
+// newLogger собирает *zap.Logger с уровнем и форматом из конфига.
+func newLogger(cfg config.Config) (*zap.Logger, error) {
+	level, err := zapcore.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	zapCfg := zap.NewProductionConfig()
+	if cfg.LogFormat == "console" {
+		zapCfg = zap.NewDevelopmentConfig()
+	}
+	zapCfg.Level = zap.NewAtomicLevelAt(level)
+
+	return zapCfg.Build()
+}
 
 func main() {
-	// Инициализация Zap
-	logger, err := zap.NewProduction()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Unable to parse config: %v", err)
+	}
+
+	logger, err := newLogger(cfg)
 	if err != nil {
 		log.Fatalf("Unable to initialize logger: %v", err)
 	}
 	defer logger.Sync()
 	sugar := logger.Sugar()
 
-	// Получение строки подключения из переменной окружения
-	dsn := os.Getenv("DATABASE_URL")
-	if dsn == "" {
-		sugar.Fatal("DATABASE_URL environment variable is not set")
-	}
+	auth.SetSecret([]byte(cfg.JWTSecret))
 
-	sugar.Infof("Connecting to database using DSN: %s", dsn)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	ctx := context.Background()
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN())
+	if err != nil {
+		sugar.Fatalf("Unable to parse database config: %v", err)
+	}
+	poolCfg.MaxConns = cfg.PGMaxConns
 
-	DB, err = pgxpool.New(ctx, dsn)
+	db, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		sugar.Fatalf("Unable to connect to database: %v", err)
 	}
-	defer DB.Close()
+	defer db.Close()
 
 	sugar.Info("Connected to database.")
 
-	// Инициализация маршрутов с логгером
-	r := router.InitRouter(DB, sugar)
+	repo := repository.NewClientRepo(db)
+
+	httpSrv := &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           router.InitRouter(db, repo, sugar),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      10 * time.Second,
+	}
+	grpcSrv := grpcserver.New(repo, sugar)
+
+	var sink outbox.Sink = outbox.NewLogSink(sugar)
+	if cfg.OutboxWebhookURL != "" {
+		sink = outbox.NewWebhookSink(cfg.OutboxWebhookURL)
+	}
+	publisher := outbox.NewPublisher(db, sink, sugar, cfg.OutboxPollInterval, cfg.OutboxMaxAttempts)
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		publisher.Run(gCtx)
+		return nil
+	})
+
+	g.Go(func() error {
+		sugar.Infof("HTTP server is running on %s", cfg.HTTPAddr)
+		if err := httpSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		sugar.Infof("gRPC server is running on %s", cfg.GRPCAddr)
+		return grpcserver.Serve(grpcSrv, cfg.GRPCAddr)
+	})
+
+	g.Go(func() error {
+		<-gCtx.Done()
+		sugar.Info("Shutdown signal received, draining in-flight requests...")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			sugar.Errorf("Error shutting down HTTP server: %v", err)
+		}
+		grpcSrv.GracefulStop()
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		sugar.Fatalf("Server error: %v", err)
+	}
 
-	// Запуск HTTP-сервера
-	sugar.Info("Server is running on http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", r))
+	sugar.Info("Server stopped gracefully.")
 }
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// ctxKey — приватный тип для ключей контекста, чтобы избежать коллизий с другими пакетами.
+type ctxKey string
+
+const (
+	loggerCtxKey    ctxKey = "middleware.logger"
+	requestIDCtxKey ctxKey = "middleware.request_id"
+)
+
+// WithLogger кладёт в контекст логгер, уже привязанный к текущему request_id.
+func WithLogger(ctx context.Context, logger *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey, logger)
+}
+
+// LoggerFromContext достаёт логгер, положенный RequestID middleware. Если middleware
+// не было в цепочке (например, в тестах), возвращает no-op логгер вместо паники.
+func LoggerFromContext(ctx context.Context) *zap.SugaredLogger {
+	if logger, ok := ctx.Value(loggerCtxKey).(*zap.SugaredLogger); ok {
+		return logger
+	}
+	return zap.NewNop().Sugar()
+}
+
+// withRequestID кладёт в контекст X-Request-ID текущего запроса.
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey, id)
+}
+
+// RequestIDFromContext достаёт X-Request-ID текущего запроса, если он был установлен.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey).(string)
+	return id
+}
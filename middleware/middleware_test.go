@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInstrumentLabelsByRouteNotPath(t *testing.T) {
+	handle := Instrument("/clients/:id")(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, id := range []string{"11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"} {
+		req := httptest.NewRequest(http.MethodGet, "/clients/"+id, nil)
+		rec := httptest.NewRecorder()
+		handle(rec, req, httprouter.Params{{Key: "id", Value: id}})
+	}
+
+	count := testutil.CollectAndCount(httpRequestDuration)
+	if count != 1 {
+		t.Errorf("httpRequestDuration has %d label combinations, want 1 (one per route, not per path)", count)
+	}
+}
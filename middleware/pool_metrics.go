@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolStatsCollector reports live pgxpool.Pool stats as Prometheus gauges on every
+// /metrics scrape, instead of sampling them on a timer.
+type poolStatsCollector struct {
+	pool *pgxpool.Pool
+
+	acquired *prometheus.Desc
+	idle     *prometheus.Desc
+	max      *prometheus.Desc
+}
+
+// NewPoolStatsCollector returns a collector exposing pgx_pool_acquired,
+// pgx_pool_idle and pgx_pool_max for the given pool. It does not register it;
+// the caller registers it with a Prometheus registry.
+func NewPoolStatsCollector(pool *pgxpool.Pool) prometheus.Collector {
+	return &poolStatsCollector{
+		pool:     pool,
+		acquired: prometheus.NewDesc("pgx_pool_acquired", "Number of connections currently acquired from the pool.", nil, nil),
+		idle:     prometheus.NewDesc("pgx_pool_idle", "Number of idle connections in the pool.", nil, nil),
+		max:      prometheus.NewDesc("pgx_pool_max", "Maximum number of connections the pool can hold.", nil, nil),
+	}
+}
+
+func (c *poolStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.max
+}
+
+func (c *poolStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(stat.MaxConns()))
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	publishedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "outbox_published_total",
+		Help: "Total number of outbox events successfully published.",
+	})
+	failedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "outbox_failed_total",
+		Help: "Total number of outbox publish attempts that failed.",
+	})
+)
+
+// Event — строка outbox_events, готовая к публикации в Sink.
+type Event struct {
+	ID          string
+	AggregateID string
+	Type        string
+	Payload     []byte
+	Attempts    int
+}
+
+// Sink публикует outbox-события во внешнюю систему (Kafka, NATS, webhook и т.д.).
+type Sink interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Publisher опрашивает outbox_events и публикует неотправленные события через Sink,
+// реализуя transactional outbox поверх таблицы, в которую пишут репозитории в той же
+// транзакции, что и саму мутацию.
+type Publisher struct {
+	db           *pgxpool.Pool
+	sink         Sink
+	logger       *zap.SugaredLogger
+	pollInterval time.Duration
+	maxAttempts  int
+	batchSize    int
+}
+
+// NewPublisher создаёт Publisher для заданного Sink.
+func NewPublisher(db *pgxpool.Pool, sink Sink, logger *zap.SugaredLogger, pollInterval time.Duration, maxAttempts int) *Publisher {
+	return &Publisher{
+		db:           db,
+		sink:         sink,
+		logger:       logger,
+		pollInterval: pollInterval,
+		maxAttempts:  maxAttempts,
+		batchSize:    100,
+	}
+}
+
+// Run опрашивает outbox_events до отмены ctx. Предназначен для запуска в отдельной
+// горутине из main.go.
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.publishBatch(ctx); err != nil {
+				p.logger.Errorf("Error publishing outbox batch: %v", err)
+			}
+		}
+	}
+}
+
+// publishBatch забирает партию готовых к отправке событий с FOR UPDATE SKIP LOCKED,
+// чтобы несколько инстансов сервиса могли опрашивать outbox_events одновременно
+// без повторной обработки одних и тех же строк.
+func (p *Publisher) publishBatch(ctx context.Context) error {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id, aggregate_id, type, payload, attempts
+		FROM outbox_events
+		WHERE published_at IS NULL AND attempts < $1 AND next_attempt_at <= now()
+		ORDER BY created_at
+		FOR UPDATE SKIP LOCKED
+		LIMIT $2`
+
+	rows, err := tx.Query(ctx, query, p.maxAttempts, p.batchSize)
+	if err != nil {
+		return err
+	}
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.AggregateID, &e.Type, &e.Payload, &e.Attempts); err != nil {
+			rows.Close()
+			return err
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		if err := p.sink.Publish(ctx, e); err != nil {
+			p.logger.Warnf("Failed to publish outbox event %s (attempt %d): %v", e.ID, e.Attempts+1, err)
+			failedTotal.Inc()
+
+			backoff := time.Duration(1<<uint(e.Attempts)) * time.Second
+			_, updErr := tx.Exec(ctx,
+				`UPDATE outbox_events SET attempts = attempts + 1, next_attempt_at = now() + $2 WHERE id = $1`,
+				e.ID, backoff)
+			if updErr != nil {
+				return updErr
+			}
+			continue
+		}
+
+		publishedTotal.Inc()
+		if _, err := tx.Exec(ctx, `UPDATE outbox_events SET published_at = now() WHERE id = $1`, e.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WebhookSink публикует каждое событие как POST-запрос с телом payload к заданному URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink создаёт Sink, отправляющий события на webhookURL.
+func NewWebhookSink(webhookURL string) *WebhookSink {
+	return &WebhookSink{
+		url:    webhookURL,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event Event) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", event.Type)
+	req.Header.Set("X-Event-Id", event.ID)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogSink just logs events instead of publishing them. Useful as a default when no
+// external sink (Kafka, NATS, webhook) is configured, e.g. in local development.
+type LogSink struct {
+	logger *zap.SugaredLogger
+}
+
+// NewLogSink создаёт Sink, который логирует события вместо реальной публикации.
+func NewLogSink(logger *zap.SugaredLogger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Publish(_ context.Context, event Event) error {
+	s.logger.Infof("Outbox event %s (%s) for aggregate %s: %s", event.ID, event.Type, event.AggregateID, event.Payload)
+	return nil
+}
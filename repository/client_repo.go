@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNotFound сигнализирует о том, что клиент с таким ID не существует.
+var ErrNotFound = errors.New("client not found")
+
+// Client — доменная модель клиента, общая для HTTP и gRPC транспортов.
+type Client struct {
+	ID        string
+	Name      string
+	Phone     string
+	Email     string
+	Comment   string
+	CreatedAt time.Time
+}
+
+// sortColumns — белый список колонок, допустимых в ?sort=, чтобы не собирать
+// ORDER BY из непроверенного пользовательского ввода.
+var sortColumns = map[string]string{
+	"name":       "name",
+	"created_at": "created_at",
+}
+
+// ListCursorParams описывает keyset-пагинацию, фильтрацию и сортировку для ListCursor.
+type ListCursorParams struct {
+	Cursor string
+	Limit  int
+	Sort   string // "name" | "created_at"
+	Order  string // "asc" | "desc"
+	Query  string // ILIKE по name/email/phone
+	Email  string // точное совпадение по email
+}
+
+// Page — страница результатов keyset-пагинации с курсором на следующую страницу.
+type Page struct {
+	Items      []Client
+	NextCursor string
+}
+
+// ClientRepo инкапсулирует доступ к хранилищу клиентов, чтобы транспортные
+// слои (handlers, grpcserver) не зависели напрямую от pgx.
+type ClientRepo interface {
+	Create(ctx context.Context, client Client) (Client, error)
+	Get(ctx context.Context, id string) (Client, error)
+	List(ctx context.Context, limit, offset int) ([]Client, error)
+	ListCursor(ctx context.Context, params ListCursorParams) (Page, error)
+	Count(ctx context.Context, params ListCursorParams) (int64, error)
+	Update(ctx context.Context, client Client) (Client, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// pgClientRepo — реализация ClientRepo поверх pgxpool.Pool.
+type pgClientRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewClientRepo создаёт ClientRepo, работающий с базой данных Postgres.
+func NewClientRepo(db *pgxpool.Pool) ClientRepo {
+	return &pgClientRepo{db: db}
+}
+
+// Outbox event types written alongside client mutations; consumed by outbox.Publisher.
+const (
+	eventClientCreated = "client.created"
+	eventClientUpdated = "client.updated"
+	eventClientDeleted = "client.deleted"
+)
+
+// insertOutboxEvent записывает событие в outbox_events в рамках той же транзакции,
+// что и сама мутация, гарантируя атомарность записи данных и публикации события.
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, aggregateID, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox_events (id, aggregate_id, type, payload) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(ctx, query, uuid.New().String(), aggregateID, eventType, data)
+	return err
+}
+
+func (r *pgClientRepo) Create(ctx context.Context, client Client) (Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Client{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO clients (id, name, phone, email, comment) VALUES ($1, $2, $3, $4, $5) RETURNING created_at`
+	if err := tx.QueryRow(ctx, query, client.ID, client.Name, client.Phone, client.Email, client.Comment).Scan(&client.CreatedAt); err != nil {
+		return Client{}, err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, client.ID, eventClientCreated, client); err != nil {
+		return Client{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Client{}, err
+	}
+	return client, nil
+}
+
+func (r *pgClientRepo) Get(ctx context.Context, id string) (Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var client Client
+	query := `SELECT id, name, phone, email, comment, created_at FROM clients WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(&client.ID, &client.Name, &client.Phone, &client.Email, &client.Comment, &client.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Client{}, ErrNotFound
+		}
+		return Client{}, err
+	}
+	return client, nil
+}
+
+func (r *pgClientRepo) List(ctx context.Context, limit, offset int) ([]Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `SELECT id, name, phone, email, comment, created_at FROM clients LIMIT $1 OFFSET $2`
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients := []Client{}
+	for rows.Next() {
+		var client Client
+		if err := rows.Scan(&client.ID, &client.Name, &client.Phone, &client.Email, &client.Comment, &client.CreatedAt); err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, rows.Err()
+}
+
+// whereFilters строит предложение WHERE для q/email и возвращает его вместе с
+// уже использованными позиционными аргументами, чтобы вызывающий код мог
+// продолжить нумерацию $N с пагинацией/сортировкой.
+func whereFilters(params ListCursorParams) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if params.Query != "" {
+		args = append(args, "%"+params.Query+"%")
+		conds = append(conds, fmt.Sprintf("(name ILIKE $%d OR email ILIKE $%d OR phone ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	if params.Email != "" {
+		args = append(args, params.Email)
+		conds = append(conds, fmt.Sprintf("email = $%d", len(args)))
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// cursorSortArg приводит LastSortValue курсора к типу, ожидаемому колонкой sortCol,
+// чтобы pgx биндил его как time.Time для created_at, а не как голую строку —
+// иначе Postgres не может сравнить timestamptz-колонку с текстовым параметром.
+func cursorSortArg(sortCol, lastSortValue string) (interface{}, error) {
+	if sortCol != "created_at" {
+		return lastSortValue, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, lastSortValue)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// ListCursor возвращает страницу клиентов по keyset-пагинации: условие на пару (sort_col, id)
+// после позиции курсора (> для asc, < для desc) вместо OFFSET, чтобы запрос оставался
+// O(log n) независимо от глубины пагинации.
+func (r *pgClientRepo) ListCursor(ctx context.Context, params ListCursorParams) (Page, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	sortCol, ok := sortColumns[params.Sort]
+	if !ok {
+		sortCol = "created_at"
+	}
+	order := "ASC"
+	if params.Order == "desc" {
+		order = "DESC"
+	}
+	cmp := ">"
+	if order == "DESC" {
+		cmp = "<"
+	}
+
+	where, args := whereFilters(params)
+
+	if params.Cursor != "" {
+		c, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return Page{}, err
+		}
+
+		sortArg, err := cursorSortArg(sortCol, c.LastSortValue)
+		if err != nil {
+			return Page{}, err
+		}
+
+		n := len(args)
+		args = append(args, sortArg, c.LastID)
+		sortPlaceholder, idPlaceholder := fmt.Sprintf("$%d", n+1), fmt.Sprintf("$%d", n+2)
+
+		// Expanded as a tie-break OR instead of a (sort_col, id) row-value comparison:
+		// a row comparison requires both sides to already share a concrete type, which
+		// Postgres cannot infer for a mixed timestamptz/uuid pair bound as parameters.
+		cursorCond := fmt.Sprintf(
+			"(%s %s %s OR (%s = %s AND id %s %s))",
+			sortCol, cmp, sortPlaceholder, sortCol, sortPlaceholder, cmp, idPlaceholder,
+		)
+		if where == "" {
+			where = " WHERE " + cursorCond
+		} else {
+			where += " AND " + cursorCond
+		}
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(
+		`SELECT id, name, phone, email, comment, created_at FROM clients%s ORDER BY %s %s, id %s LIMIT $%d`,
+		where, sortCol, order, order, len(args),
+	)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return Page{}, err
+	}
+	defer rows.Close()
+
+	var items []Client
+	for rows.Next() {
+		var client Client
+		if err := rows.Scan(&client.ID, &client.Name, &client.Phone, &client.Email, &client.Comment, &client.CreatedAt); err != nil {
+			return Page{}, err
+		}
+		items = append(items, client)
+	}
+	if err := rows.Err(); err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Items: items}
+	if len(items) == limit {
+		last := items[len(items)-1]
+		lastSortValue := last.Name
+		if sortCol == "created_at" {
+			lastSortValue = last.CreatedAt.Format(time.RFC3339Nano)
+		}
+		page.NextCursor = encodeCursor(cursor{LastSortValue: lastSortValue, LastID: last.ID})
+	}
+	return page, nil
+}
+
+// Count возвращает общее число клиентов, удовлетворяющих тем же фильтрам, что и ListCursor.
+func (r *pgClientRepo) Count(ctx context.Context, params ListCursorParams) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	where, args := whereFilters(params)
+	query := fmt.Sprintf(`SELECT count(*) FROM clients%s`, where)
+
+	var total int64
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *pgClientRepo) Update(ctx context.Context, client Client) (Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Client{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE clients SET name = $1, phone = $2, email = $3, comment = $4 WHERE id = $5`
+	cmdTag, err := tx.Exec(ctx, query, client.Name, client.Phone, client.Email, client.Comment, client.ID)
+	if err != nil {
+		return Client{}, err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return Client{}, ErrNotFound
+	}
+
+	if err := insertOutboxEvent(ctx, tx, client.ID, eventClientUpdated, client); err != nil {
+		return Client{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Client{}, err
+	}
+	return client, nil
+}
+
+func (r *pgClientRepo) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `DELETE FROM clients WHERE id = $1`
+	cmdTag, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := insertOutboxEvent(ctx, tx, id, eventClientDeleted, map[string]string{"id": id}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
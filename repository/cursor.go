@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor сигнализирует о том, что переданный курсор не удалось разобрать.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursor хранит позицию последней прочитанной строки для keyset-пагинации.
+type cursor struct {
+	LastSortValue string `json:"last_sort_value"`
+	LastID        string `json:"last_id"`
+}
+
+// encodeCursor сериализует cursor в непрозрачную base64-строку для клиента.
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.URLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor разбирает курсор, полученный от клиента в ?cursor=.
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	raw, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeCursorRoundTrip(t *testing.T) {
+	want := cursor{LastSortValue: time.Now().UTC().Format(time.RFC3339Nano), LastID: "11111111-1111-1111-1111-111111111111"}
+
+	got, err := decodeCursor(encodeCursor(want))
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if got != want {
+		t.Fatalf("decodeCursor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, s := range []string{"not-base64!!!", "aGVsbG8="} {
+		if _, err := decodeCursor(s); err != ErrInvalidCursor {
+			t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
+
+func TestCursorSortArg(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Nanosecond)
+
+	t.Run("created_at parses to time.Time", func(t *testing.T) {
+		arg, err := cursorSortArg("created_at", now.Format(time.RFC3339Nano))
+		if err != nil {
+			t.Fatalf("cursorSortArg() error = %v", err)
+		}
+		got, ok := arg.(time.Time)
+		if !ok {
+			t.Fatalf("cursorSortArg() = %T, want time.Time", arg)
+		}
+		if !got.Equal(now) {
+			t.Errorf("cursorSortArg() = %v, want %v", got, now)
+		}
+	})
+
+	t.Run("created_at rejects unparsable value", func(t *testing.T) {
+		if _, err := cursorSortArg("created_at", "not-a-timestamp"); err != ErrInvalidCursor {
+			t.Errorf("cursorSortArg() error = %v, want ErrInvalidCursor", err)
+		}
+	})
+
+	t.Run("name passes through as string", func(t *testing.T) {
+		arg, err := cursorSortArg("name", "Alice")
+		if err != nil {
+			t.Fatalf("cursorSortArg() error = %v", err)
+		}
+		if arg != "Alice" {
+			t.Errorf("cursorSortArg() = %v, want %q", arg, "Alice")
+		}
+	})
+}
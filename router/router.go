@@ -1,28 +1,68 @@
 package router
 
 import (
+	"client_service/auth"
 	"client_service/handlers"
+	"client_service/middleware"
+	"client_service/repository"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"net/http"
 )
 
-// InitRouter инициализирует маршруты и принимает подключение к базе данных и логгер
-func InitRouter(db *pgxpool.Pool, logger *zap.SugaredLogger) *httprouter.Router {
+// InitRouter инициализирует маршруты и принимает подключение к базе данных, репозиторий клиентов и логгер.
+// Возвращает http.Handler, обёрнутый в цепочку middleware (request ID, логирование).
+func InitRouter(db *pgxpool.Pool, repo repository.ClientRepo, logger *zap.SugaredLogger) http.Handler {
 	router := httprouter.New()
 
+	// auth
+	router.POST("/auth/login", middleware.Instrument("/auth/login")(auth.Login(db, logger)))
+	router.POST("/auth/refresh", middleware.Instrument("/auth/refresh")(auth.Refresh(db, logger)))
+
+	requireAdminOrViewer := auth.RequireRole(db, logger, "admin", "viewer")
+	requireAdmin := auth.RequireRole(db, logger, "admin")
+
+	router.POST("/auth/logout", middleware.Instrument("/auth/logout")(requireAdminOrViewer(auth.Logout(db, logger))))
+	router.POST("/auth/users", middleware.Instrument("/auth/users")(requireAdmin(auth.CreateUser(db, logger))))
+
 	//crud
-	router.POST("/clients", handlers.CreateClient(db, logger))
-	router.GET("/clients/:id", handlers.GetClientByID(db, logger))
-	router.GET("/clients", handlers.GetClients(db, logger))
-	router.PUT("/clients/:id", handlers.UpdateClient(db, logger))
-	router.DELETE("/clients/:id", handlers.DeleteClient(db, logger))
+	router.POST("/clients", middleware.Instrument("/clients")(requireAdmin(handlers.CreateClient(repo))))
+	// Registered as a separate top-level route, not /clients/count: httprouter v1.3.0
+	// forbids a static child and a wildcard (:id) at the same path segment.
+	router.GET("/clients_count", middleware.Instrument("/clients_count")(requireAdminOrViewer(handlers.GetClientsCount(repo))))
+	router.GET("/clients/:id", middleware.Instrument("/clients/:id")(requireAdminOrViewer(handlers.GetClientByID(repo))))
+	router.GET("/clients", middleware.Instrument("/clients")(requireAdminOrViewer(handlers.GetClients(repo))))
+	router.PUT("/clients/:id", middleware.Instrument("/clients/:id")(requireAdmin(handlers.UpdateClient(repo))))
+	router.DELETE("/clients/:id", middleware.Instrument("/clients/:id")(requireAdmin(handlers.DeleteClient(repo))))
 
 	router.GET("/", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Service is healthy"))
 	})
 
-	return router
+	// livez — процесс жив, без обращения к зависимостям
+	router.GET("/livez", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	// readyz — сервис готов принимать трафик, только если доступна база данных
+	router.GET("/readyz", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if err := db.Ping(r.Context()); err != nil {
+			logger.Warnf("Readiness check failed: %v", err)
+			http.Error(w, "Not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	// metrics — HTTP latency histograms и статистика пула соединений pgx
+	prometheus.MustRegister(middleware.NewPoolStatsCollector(db))
+	router.Handler(http.MethodGet, "/metrics", promhttp.Handler())
+
+	return middleware.Chain(router, logger)
 }
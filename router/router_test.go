@@ -0,0 +1,51 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"client_service/repository"
+
+	"go.uber.org/zap"
+)
+
+// fakeClientRepo — заглушка repository.ClientRepo, нужна только для того, чтобы
+// InitRouter могла собрать маршруты; ни один метод в этом тесте не вызывается.
+type fakeClientRepo struct{}
+
+func (fakeClientRepo) Create(ctx context.Context, client repository.Client) (repository.Client, error) {
+	return repository.Client{}, nil
+}
+func (fakeClientRepo) Get(ctx context.Context, id string) (repository.Client, error) {
+	return repository.Client{}, nil
+}
+func (fakeClientRepo) List(ctx context.Context, limit, offset int) ([]repository.Client, error) {
+	return nil, nil
+}
+func (fakeClientRepo) ListCursor(ctx context.Context, params repository.ListCursorParams) (repository.Page, error) {
+	return repository.Page{}, nil
+}
+func (fakeClientRepo) Count(ctx context.Context, params repository.ListCursorParams) (int64, error) {
+	return 0, nil
+}
+func (fakeClientRepo) Update(ctx context.Context, client repository.Client) (repository.Client, error) {
+	return repository.Client{}, nil
+}
+func (fakeClientRepo) Delete(ctx context.Context, id string) error { return nil }
+
+// TestInitRouter проверяет, что маршруты собираются без паники. httprouter паникует
+// при регистрации, если статический и wildcard-сегмент конфликтуют на одном уровне
+// (например /clients/count рядом с /clients/:id) — это единственный способ поймать
+// такую ошибку, не дожидаясь падения сервиса при старте.
+func TestInitRouter(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("InitRouter panicked: %v", r)
+		}
+	}()
+
+	handler := InitRouter(nil, fakeClientRepo{}, zap.NewNop().Sugar())
+	if handler == nil {
+		t.Fatal("InitRouter() returned nil handler")
+	}
+}